@@ -5,12 +5,20 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 )
 
 func (s *Systemd) Command(rootCmd *cobra.Command) {
 	var persistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if userScope, _ := cmd.Flags().GetBool("user"); userScope {
+			s.Scope = ScopeUser
+			return nil
+		}
+		s.Scope = ScopeSystem
 		_user, err := user.Current()
 		if err != nil {
 			return err
@@ -29,7 +37,7 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 		PersistentPreRunE: persistentPreRunE,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			multi, _ := cmd.Flags().GetBool("multi")
-			return s.Install(multi, args...)
+			return s.Install(multi, unitOptionsFromFlags(cmd), args...)
 		},
 	}
 
@@ -51,6 +59,7 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 		PersistentPreRunE: persistentPreRunE,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			num, _ := cmd.Flags().GetInt("num")
+			s.Parallel, _ = cmd.Flags().GetInt("parallel")
 			return s.Start(num, args...)
 		},
 	}
@@ -62,6 +71,7 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 		PersistentPreRunE: persistentPreRunE,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			all, _ := cmd.Flags().GetBool("all")
+			s.Parallel, _ = cmd.Flags().GetInt("parallel")
 			return s.Stop(all, args...)
 		},
 	}
@@ -93,6 +103,7 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 		PersistentPreRunE: persistentPreRunE,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			all, _ := cmd.Flags().GetBool("all")
+			s.Parallel, _ = cmd.Flags().GetInt("parallel")
 			return s.Restart(all, args...)
 		},
 	}
@@ -105,6 +116,7 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 		PersistentPreRunE: persistentPreRunE,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			all, _ := cmd.Flags().GetBool("all")
+			s.Parallel, _ = cmd.Flags().GetInt("parallel")
 			return s.Kill(all, args...)
 		},
 	}
@@ -116,6 +128,7 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 		PersistentPreRunE: persistentPreRunE,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			all, _ := cmd.Flags().GetBool("all")
+			s.Parallel, _ = cmd.Flags().GetInt("parallel")
 			return s.Reload(all, args...)
 		},
 	}
@@ -132,6 +145,85 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 		},
 	}
 
+	var journalCmd = &cobra.Command{
+		GroupID:           "systemd",
+		Use:               "logs [tag]...",
+		Short:             "Stream sd-journal entries for the managed unit(s)",
+		Aliases:           []string{"journal", "log"},
+		PersistentPreRunE: persistentPreRunE,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			follow, _ := cmd.Flags().GetBool("follow")
+			lines, _ := cmd.Flags().GetInt("lines")
+			sinceFlag, _ := cmd.Flags().GetString("since")
+			all, _ := cmd.Flags().GetBool("all")
+
+			tags := args
+			if all {
+				items, err := s.Status(false)
+				if err != nil {
+					return err
+				}
+				tags = nil
+				prefix := s.Name + "@"
+				for _, item := range items {
+					if tag, ok := strings.CutPrefix(item.Name, prefix); ok {
+						tags = append(tags, strings.TrimSuffix(tag, ".service"))
+					}
+				}
+			}
+
+			var since time.Time
+			if sinceFlag != "" {
+				if d, err := time.ParseDuration(sinceFlag); err == nil {
+					since = time.Now().Add(-d)
+				} else if t, err := time.Parse(time.RFC3339, sinceFlag); err == nil {
+					since = t
+				} else {
+					return fmt.Errorf("invalid --since %q: use a duration (1h) or RFC3339 timestamp", sinceFlag)
+				}
+			}
+
+			entries, err := s.Journal(cmd.Context(), tags, follow, since, lines)
+			if err != nil {
+				return err
+			}
+			for entry := range entries {
+				fields := []zap.Field{zap.String("unit", entry.Unit), zap.Time("time", entry.Timestamp)}
+				switch {
+				case entry.Priority <= 3:
+					s.logger.Error(entry.Message, fields...)
+				case entry.Priority <= 4:
+					s.logger.Warn(entry.Message, fields...)
+				default:
+					s.logger.Info(entry.Message, fields...)
+				}
+			}
+			return nil
+		},
+	}
+
+	var watchCmd = &cobra.Command{
+		GroupID:           "systemd",
+		Use:               "watch",
+		Short:             "Live-stream ActiveState/SubState transitions",
+		PersistentPreRunE: persistentPreRunE,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			events, err := s.Watch(cmd.Context())
+			if err != nil {
+				return err
+			}
+			for event := range events {
+				s.logger.Info("Watch",
+					zap.String("name", event.Name),
+					zap.String("active", event.PrevActiveState+" -> "+event.ActiveState),
+					zap.String("sub", event.PrevSubState+" -> "+event.SubState),
+					zap.Time("time", event.Timestamp),
+				)
+			}
+			return nil
+		},
+	}
+
 	var unitCmd = &cobra.Command{
 		GroupID:           "systemd",
 		Hidden:            true,
@@ -145,7 +237,7 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 				if err != nil {
 					return err
 				}
-				buf, err := CreateUnit(multi, s.Name, s.Description, execPath, args...)
+				buf, err := CreateUnit(multi, s.Name, s.Description, execPath, s.Scope, unitOptionsFromFlags(cmd), args...)
 				if err != nil {
 					return err
 				}
@@ -168,14 +260,149 @@ func (s *Systemd) Command(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(
 		installCmd, removeCmd, reloadCmd, unitCmd,
 		startCmd, stopCmd, killCmd, restartCmd, statusCmd,
-		enableCmd, disableCmd,
+		enableCmd, disableCmd, journalCmd, watchCmd,
 	)
 	installCmd.Flags().BoolP("multi", "m", false, "Use template unit service")
+	addUnitOptionFlags(installCmd)
+	addUnitOptionFlags(unitCmd)
+	addTimerSocketFlags(installCmd)
 	startCmd.Flags().IntP("num", "n", 0, "Num of Instances for start")
 	stopCmd.Flags().BoolP("all", "a", false, "Stop all Instances")
 	restartCmd.Flags().BoolP("all", "a", false, "Restart all Instances")
 	killCmd.Flags().BoolP("all", "a", false, "Kill all Instances")
 	reloadCmd.Flags().BoolP("all", "a", false, "Reload all Instances")
+	for _, c := range []*cobra.Command{startCmd, stopCmd, restartCmd, killCmd, reloadCmd} {
+		c.Flags().IntP("parallel", "p", defaultParallel, "Max concurrent dbus jobs for bulk operations")
+	}
 	unitCmd.Flags().BoolP("template", "t", false, "Show template unit service file")
 	unitCmd.Flags().BoolP("multi", "m", false, "Use template unit service")
+	journalCmd.Flags().BoolP("follow", "f", false, "Keep streaming new entries")
+	journalCmd.Flags().IntP("lines", "n", 0, "Number of lines to show from the tail")
+	journalCmd.Flags().String("since", "", "Show entries since a duration (1h) or RFC3339 timestamp")
+	journalCmd.Flags().BoolP("all", "a", false, "Show entries for all instances of a template unit")
+	rootCmd.PersistentFlags().BoolP("user", "u", false, "Manage per-user (rootless) systemd units")
+}
+
+// addUnitOptionFlags registers the UnitOptions surface shared by install and
+// unit so both render the same unit file for the same flags.
+func addUnitOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().String("restart", "", "Restart policy (no, on-failure, always)")
+	cmd.Flags().Int("restart-sec", 0, "Seconds to sleep before restarting")
+	cmd.Flags().Int("timeout-start-sec", 0, "Seconds to wait for start-up")
+	cmd.Flags().Int("timeout-stop-sec", 0, "Seconds to wait for shutdown")
+	cmd.Flags().StringSlice("after", nil, "Units to order after (repeatable)")
+	cmd.Flags().StringSlice("requires", nil, "Units to require (repeatable)")
+	cmd.Flags().StringSlice("wants", nil, "Units to want (repeatable)")
+	cmd.Flags().StringSlice("part-of", nil, "Units this is PartOf (repeatable)")
+	cmd.Flags().String("workdir", "", "WorkingDirectory for the service")
+	cmd.Flags().StringSlice("env", nil, "Environment KEY=VALUE (repeatable)")
+	cmd.Flags().StringSlice("env-file", nil, "EnvironmentFile path (repeatable)")
+	cmd.Flags().String("exec-user", "", "User to run the service as")
+	cmd.Flags().String("exec-group", "", "Group to run the service as")
+	cmd.Flags().Int("limit-nofile", 0, "LimitNOFILE")
+	cmd.Flags().Int("limit-nproc", 0, "LimitNPROC")
+	cmd.Flags().String("memory-max", "", "MemoryMax, e.g. 512M")
+	cmd.Flags().String("cpu-quota", "", "CPUQuota, e.g. 50%")
+	cmd.Flags().String("kill-mode", "", "KillMode (control-group, process, mixed, none)")
+	cmd.Flags().String("kill-signal", "", "KillSignal, e.g. SIGTERM")
+	cmd.Flags().Int("watchdog-sec", 0, "WatchdogSec for sd_notify keep-alive")
+	cmd.Flags().String("exec-reload", "", "ExecReload command")
+	cmd.Flags().StringSlice("exec-start-pre", nil, "ExecStartPre command (repeatable)")
+	cmd.Flags().StringSlice("exec-start-post", nil, "ExecStartPost command (repeatable)")
+}
+
+// unitOptionsFromFlags builds a *UnitOptions from the flags addUnitOptionFlags
+// registered, or nil if none of them were set so Install/CreateUnit fall back
+// to their minimal defaults.
+func unitOptionsFromFlags(cmd *cobra.Command) *UnitOptions {
+	if !cmd.Flags().Changed("restart") && !cmd.Flags().Changed("restart-sec") &&
+		!cmd.Flags().Changed("timeout-start-sec") && !cmd.Flags().Changed("timeout-stop-sec") &&
+		!cmd.Flags().Changed("after") && !cmd.Flags().Changed("requires") &&
+		!cmd.Flags().Changed("wants") && !cmd.Flags().Changed("part-of") &&
+		!cmd.Flags().Changed("workdir") && !cmd.Flags().Changed("env") &&
+		!cmd.Flags().Changed("env-file") && !cmd.Flags().Changed("exec-user") &&
+		!cmd.Flags().Changed("exec-group") && !cmd.Flags().Changed("limit-nofile") &&
+		!cmd.Flags().Changed("limit-nproc") && !cmd.Flags().Changed("memory-max") &&
+		!cmd.Flags().Changed("cpu-quota") && !cmd.Flags().Changed("kill-mode") &&
+		!cmd.Flags().Changed("kill-signal") && !cmd.Flags().Changed("watchdog-sec") &&
+		!cmd.Flags().Changed("exec-reload") && !cmd.Flags().Changed("exec-start-pre") &&
+		!cmd.Flags().Changed("exec-start-post") && !cmd.Flags().Changed("timer") &&
+		!cmd.Flags().Changed("socket") {
+		return nil
+	}
+	opts := &UnitOptions{}
+	opts.Restart, _ = cmd.Flags().GetString("restart")
+	opts.RestartSec, _ = cmd.Flags().GetInt("restart-sec")
+	opts.TimeoutStartSec, _ = cmd.Flags().GetInt("timeout-start-sec")
+	opts.TimeoutStopSec, _ = cmd.Flags().GetInt("timeout-stop-sec")
+	opts.After, _ = cmd.Flags().GetStringSlice("after")
+	opts.Requires, _ = cmd.Flags().GetStringSlice("requires")
+	opts.Wants, _ = cmd.Flags().GetStringSlice("wants")
+	opts.PartOf, _ = cmd.Flags().GetStringSlice("part-of")
+	opts.WorkingDirectory, _ = cmd.Flags().GetString("workdir")
+	opts.Environment, _ = cmd.Flags().GetStringSlice("env")
+	opts.EnvironmentFile, _ = cmd.Flags().GetStringSlice("env-file")
+	opts.User, _ = cmd.Flags().GetString("exec-user")
+	opts.Group, _ = cmd.Flags().GetString("exec-group")
+	opts.LimitNOFILE, _ = cmd.Flags().GetInt("limit-nofile")
+	opts.LimitNPROC, _ = cmd.Flags().GetInt("limit-nproc")
+	opts.MemoryMax, _ = cmd.Flags().GetString("memory-max")
+	opts.CPUQuota, _ = cmd.Flags().GetString("cpu-quota")
+	opts.KillMode, _ = cmd.Flags().GetString("kill-mode")
+	opts.KillSignal, _ = cmd.Flags().GetString("kill-signal")
+	opts.WatchdogSec, _ = cmd.Flags().GetInt("watchdog-sec")
+	opts.ExecReload, _ = cmd.Flags().GetString("exec-reload")
+	opts.ExecStartPre, _ = cmd.Flags().GetStringSlice("exec-start-pre")
+	opts.ExecStartPost, _ = cmd.Flags().GetStringSlice("exec-start-post")
+	opts.Timer = timerOptionsFromFlags(cmd)
+	opts.Socket = socketOptionsFromFlags(cmd)
+	return opts
+}
+
+// addTimerSocketFlags registers the flags install uses to ask for a
+// companion .timer and/or .socket unit alongside the service unit.
+func addTimerSocketFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("timer", false, "Also install a companion .timer unit")
+	cmd.Flags().String("on-calendar", "", "Timer OnCalendar expression")
+	cmd.Flags().String("on-boot-sec", "", "Timer OnBootSec, e.g. 5min")
+	cmd.Flags().String("on-unit-active-sec", "", "Timer OnUnitActiveSec, e.g. 1h")
+	cmd.Flags().Bool("persistent", false, "Timer Persistent=true")
+	cmd.Flags().Int("randomized-delay-sec", 0, "Timer RandomizedDelaySec")
+
+	cmd.Flags().Bool("socket", false, "Also install a companion .socket unit")
+	cmd.Flags().String("listen-stream", "", "Socket ListenStream address")
+	cmd.Flags().String("listen-datagram", "", "Socket ListenDatagram address")
+	cmd.Flags().Bool("accept", false, "Socket Accept=true (one service instance per connection)")
+	cmd.Flags().String("socket-mode", "", "Socket SocketMode, e.g. 0660")
+	cmd.Flags().String("fd-name", "", "Socket FileDescriptorName")
+}
+
+// timerOptionsFromFlags builds a *TimerOptions from the flags
+// addTimerSocketFlags registered, or nil if --timer was not set.
+func timerOptionsFromFlags(cmd *cobra.Command) *TimerOptions {
+	if timer, _ := cmd.Flags().GetBool("timer"); !timer {
+		return nil
+	}
+	opts := &TimerOptions{}
+	opts.OnCalendar, _ = cmd.Flags().GetString("on-calendar")
+	opts.OnBootSec, _ = cmd.Flags().GetString("on-boot-sec")
+	opts.OnUnitActiveSec, _ = cmd.Flags().GetString("on-unit-active-sec")
+	opts.Persistent, _ = cmd.Flags().GetBool("persistent")
+	opts.RandomizedDelaySec, _ = cmd.Flags().GetInt("randomized-delay-sec")
+	return opts
+}
+
+// socketOptionsFromFlags builds a *SocketOptions from the flags
+// addTimerSocketFlags registered, or nil if --socket was not set.
+func socketOptionsFromFlags(cmd *cobra.Command) *SocketOptions {
+	if socket, _ := cmd.Flags().GetBool("socket"); !socket {
+		return nil
+	}
+	opts := &SocketOptions{}
+	opts.ListenStream, _ = cmd.Flags().GetString("listen-stream")
+	opts.ListenDatagram, _ = cmd.Flags().GetString("listen-datagram")
+	opts.Accept, _ = cmd.Flags().GetBool("accept")
+	opts.SocketMode, _ = cmd.Flags().GetString("socket-mode")
+	opts.FileDescriptorName, _ = cmd.Flags().GetString("fd-name")
+	return opts
 }
@@ -0,0 +1,29 @@
+// Package systemd is pure Go by default. Journal (sd-journal streaming) is
+// gated behind the "journal" build tag because its implementation links
+// libsystemd via cgo; build with `go build -tags journal` and libsystemd-dev
+// installed to enable it. Without the tag, Journal returns an error and
+// every other API in this package is unaffected.
+package systemd
+
+import "time"
+
+// JournalEntry is one structured sd-journal record for a managed unit.
+type JournalEntry struct {
+	Timestamp time.Time
+	Priority  int
+	Message   string
+	Unit      string
+}
+
+// journalUnits expands tags into the concrete unit names Journal should
+// match: "name.service" with no tags, or "name@tag.service" per tag.
+func (s *Systemd) journalUnits(tags []string) []string {
+	if len(tags) == 0 {
+		return []string{s.Name + ".service"}
+	}
+	units := make([]string, len(tags))
+	for i, tag := range tags {
+		units[i] = s.Name + "@" + tag + ".service"
+	}
+	return units
+}
@@ -0,0 +1,103 @@
+//go:build journal
+
+package systemd
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/sdjournal"
+)
+
+// Journal streams sd-journal entries for the selected unit(s): s.Name+".service"
+// with no tags, or s.Name+"@tag.service" for each tag (template instances).
+// Reading starts at since if set (lines is then ignored), else lines entries
+// back from the tail if lines > 0, else at the start of the journal. With
+// follow it keeps streaming as entries arrive until ctx is done; otherwise it
+// reads up to the current end of the journal and closes the returned channel.
+//
+// Journal links against libsystemd via cgo (sdjournal). Build with
+// `-tags journal` (and libsystemd-dev installed) to enable it; without the
+// tag this method returns an error and the rest of the package stays pure Go.
+func (s *Systemd) Journal(ctx context.Context, tags []string, follow bool, since time.Time, lines int) (<-chan JournalEntry, error) {
+	units := s.journalUnits(tags)
+	j, err := sdjournal.NewJournal()
+	if err != nil {
+		return nil, err
+	}
+	for i, unit := range units {
+		if i > 0 {
+			if err = j.AddDisjunction(); err != nil {
+				j.Close()
+				return nil, err
+			}
+		}
+		if err = j.AddMatch(sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT + "=" + unit); err != nil {
+			j.Close()
+			return nil, err
+		}
+	}
+
+	switch {
+	case !since.IsZero():
+		err = j.SeekRealtimeUsec(uint64(since.UnixMicro()))
+	case lines > 0:
+		err = j.SeekTail()
+	default:
+		err = j.SeekHead()
+	}
+	if err != nil {
+		j.Close()
+		return nil, err
+	}
+	if lines > 0 && since.IsZero() {
+		if _, err = j.PreviousSkip(uint64(lines)); err != nil {
+			j.Close()
+			return nil, err
+		}
+	}
+
+	out := make(chan JournalEntry)
+	go func() {
+		defer j.Close()
+		defer close(out)
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			n, err := j.Next()
+			if err != nil {
+				s.logger.Warn(err.Error())
+				return
+			}
+			if n == 0 {
+				if !follow {
+					return
+				}
+				if w := j.Wait(time.Second); w == sdjournal.SD_JOURNAL_NOP {
+					continue
+				}
+				continue
+			}
+			entry, err := j.GetEntry()
+			if err != nil {
+				s.logger.Warn(err.Error())
+				continue
+			}
+			priority, _ := strconv.Atoi(entry.Fields[sdjournal.SD_JOURNAL_FIELD_PRIORITY])
+			record := JournalEntry{
+				Timestamp: time.UnixMicro(int64(entry.RealtimeTimestamp)),
+				Priority:  priority,
+				Message:   entry.Fields[sdjournal.SD_JOURNAL_FIELD_MESSAGE],
+				Unit:      entry.Fields[sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT],
+			}
+			select {
+			case out <- record:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
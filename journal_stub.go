@@ -0,0 +1,17 @@
+//go:build !journal
+
+package systemd
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Journal is unavailable: this binary was built without the "journal" build
+// tag, which is required to pull in sdjournal's cgo binding to libsystemd.
+// Rebuild with `-tags journal` (and libsystemd-dev installed) to stream
+// journal entries.
+func (s *Systemd) Journal(ctx context.Context, tags []string, follow bool, since time.Time, lines int) (<-chan JournalEntry, error) {
+	return nil, errors.New("systemd: Journal requires building with -tags journal (libsystemd-dev)")
+}
@@ -0,0 +1,14 @@
+package systemd
+
+import "github.com/coreos/go-systemd/v22/daemon"
+
+// NotifyWatchdog pings the service manager's watchdog via sd_notify
+// (WATCHDOG=1), matching the keep-alive UnitOptions.WatchdogSec expects: the
+// managed process must call this at an interval shorter than WatchdogSec or
+// systemd will consider it hung and act on WatchdogSec's restart/kill policy.
+// It is a no-op returning a nil error outside a unit with NotifyAccess set
+// (e.g. when running interactively), matching daemon.SdNotify's own contract.
+func (s *Systemd) NotifyWatchdog() error {
+	_, err := daemon.SdNotify(false, daemon.SdNotifyWatchdog)
+	return err
+}
@@ -0,0 +1,78 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	systemd "github.com/coreos/go-systemd/v22/dbus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestRunUnitOpsFreshChannelPerCall guards against the original bug: a
+// shared recv channel across calls races a later job's result with an
+// earlier one still waiting. Each fake op blocks until every job has
+// started, then returns a result derived only from its own unit name; if
+// results were ever crossed between goroutines, at least one would come
+// back in a logged line for a different unit.
+func TestRunUnitOpsFreshChannelPerCall(t *testing.T) {
+	units := []string{"a.service", "b.service", "c.service", "d.service"}
+
+	var started int32
+	release := make(chan struct{})
+
+	op := func(ctx context.Context, conn *systemd.Conn, unit string) (string, error) {
+		if atomic.AddInt32(&started, 1) == int32(len(units)) {
+			close(release)
+		}
+		<-release
+		return "done:" + unit, nil
+	}
+
+	core, logs := observer.New(zap.InfoLevel)
+	s := New("test", "test", "v0", "test").WithLogger(zap.New(core))
+	if err := s.runUnitOps(context.Background(), nil, units, len(units), "Started", op); err != nil {
+		t.Fatalf("runUnitOps returned error: %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != len(units) {
+		t.Fatalf("got %d log lines, want %d", len(entries), len(units))
+	}
+	for i, unit := range units {
+		if want := "Started [ " + unit + " ] done:" + unit; entries[i].Message != want {
+			t.Errorf("line %d = %q, want %q", i, entries[i].Message, want)
+		}
+	}
+}
+
+// TestRunUnitOpsAggregatesErrors asserts every failing unit is represented in
+// the joined error, instead of earlier behaviour that logged a failure and
+// silently continued past it.
+func TestRunUnitOpsAggregatesErrors(t *testing.T) {
+	units := []string{"ok.service", "fail1.service", "fail2.service"}
+
+	op := func(ctx context.Context, conn *systemd.Conn, unit string) (string, error) {
+		if unit == "ok.service" {
+			return "done", nil
+		}
+		return "", errors.New("boom")
+	}
+
+	s := New("test", "test", "v0", "test")
+	err := s.runUnitOps(context.Background(), nil, units, 2, "Started", op)
+	if err == nil {
+		t.Fatal("expected an aggregated error, got nil")
+	}
+	for _, unit := range []string{"fail1.service", "fail2.service"} {
+		if !strings.Contains(err.Error(), unit) {
+			t.Errorf("aggregated error %q does not mention %s", err.Error(), unit)
+		}
+	}
+	if strings.Contains(err.Error(), "ok.service") {
+		t.Errorf("aggregated error %q unexpectedly mentions the unit that succeeded", err.Error())
+	}
+}
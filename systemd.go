@@ -5,19 +5,32 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
-	"strconv"
 
 	systemd "github.com/coreos/go-systemd/v22/dbus"
 	"go.uber.org/zap"
 )
 
+// Scope selects whether units are managed in the system manager
+// (/etc/systemd/system) or the calling user's manager (systemd --user).
+type Scope int
+
+const (
+	ScopeSystem Scope = iota
+	ScopeUser
+)
+
 type Systemd struct {
 	logger      *zap.Logger
 	Name        string
 	Description string
 	Version     string
 	AppID       string
+	Scope       Scope
+	// Parallel caps how many dbus jobs forEachUnit fans out at once for bulk
+	// Start/Stop/Restart/Reload/Kill. 0 means defaultParallel.
+	Parallel int
 }
 
 func New(name, desc, version, appID string) *Systemd {
@@ -35,32 +48,87 @@ func (s *Systemd) WithLogger(logger *zap.Logger) *Systemd {
 	return s
 }
 
+// WithScope selects the systemd manager (system or user) that subsequent
+// operations target.
+func (s *Systemd) WithScope(scope Scope) *Systemd {
+	s.Scope = scope
+	return s
+}
+
+// unitDir returns the directory unit files are written to for the current
+// scope: /etc/systemd/system for ScopeSystem, or $XDG_CONFIG_HOME/systemd/user
+// (falling back to ~/.config/systemd/user) for ScopeUser.
+func (s *Systemd) unitDir() string {
+	if s.Scope != ScopeUser {
+		return "/etc/systemd/system"
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "systemd", "user")
+}
+
+// wantsDir returns the .wants directory units are symlinked into: the
+// default.target for user scope, multi-user.target for system scope.
+func (s *Systemd) wantsDir() string {
+	if s.Scope == ScopeUser {
+		return filepath.Join(s.unitDir(), "default.target.wants")
+	}
+	return filepath.Join(s.unitDir(), "multi-user.target.wants")
+}
+
+// connect dials the systemd manager for the current scope.
+func (s *Systemd) connect(ctx context.Context) (*systemd.Conn, error) {
+	if s.Scope == ScopeUser {
+		return systemd.NewUserConnectionContext(ctx)
+	}
+	return systemd.NewSystemConnectionContext(ctx)
+}
+
 func (s *Systemd) UnitFile(mutli bool) string {
 	if mutli {
-		return "/etc/systemd/system/" + s.Name + "@.service"
+		return filepath.Join(s.unitDir(), s.Name+"@.service")
 	}
-	return "/etc/systemd/system/" + s.Name + ".service"
+	return filepath.Join(s.unitDir(), s.Name+".service")
 }
 
-func (s *Systemd) Install(multi bool, args ...string) error {
+// Install writes and reloads the unit file for s. opts is nil for the
+// previous minimal defaults, or a *UnitOptions to render restart policy,
+// resource limits, dependencies, and hooks into the unit.
+func (s *Systemd) Install(multi bool, opts *UnitOptions, args ...string) error {
 	s.logger.Info("Install... " + s.Name)
 	execPath, err := os.Executable()
 	if err != nil {
 		return err
 	}
 	var buf []byte
-	buf, err = CreateUnit(multi, s.Name, s.Description, execPath, args...)
+	buf, err = CreateUnit(multi, s.Name, s.Description, execPath, s.Scope, opts, args...)
 	if err != nil {
 		return err
 	}
 	name := s.UnitFile(multi)
+	if err = os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
 	err = os.WriteFile(name, buf, 0644)
 	if err != nil {
 		s.logger.Error("Failed to write unit file", zap.String("name", name), zap.Error(err))
 		return err
 	}
+	if opts != nil {
+		if err = s.installTimer(opts.Timer); err != nil {
+			return err
+		}
+		if err = s.installSocket(opts.Socket); err != nil {
+			return err
+		}
+	}
 	ctx := context.Background()
-	conn, err := systemd.NewSystemConnectionContext(ctx)
+	conn, err := s.connect(ctx)
 	if err != nil {
 		return err
 	}
@@ -76,8 +144,10 @@ func (s *Systemd) Remove() error {
 		s.logger.Warn(err.Error())
 	}
 	errs := []error{
-		os.Remove("/etc/systemd/system/" + s.Name + ".service"),
-		os.Remove("/etc/systemd/system/" + s.Name + "@.service"),
+		os.Remove(s.UnitFile(false)),
+		os.Remove(s.UnitFile(true)),
+		os.Remove(s.TimerFile()),
+		os.Remove(s.SocketFile()),
 	}
 	if !slices.Contains(errs, nil) {
 		return errors.New("remove failed")
@@ -86,124 +156,77 @@ func (s *Systemd) Remove() error {
 	return nil
 }
 
-// Start the service
+// Start the service. A single unit (no num, no tags) tries name.service then
+// falls back to name@default.service, same as before. num>0 or tags fan out
+// across forEachUnit so --num 100 / --all don't serialize one dbus round
+// trip at a time.
 func (s *Systemd) Start(num int, tags ...string) error {
 	ctx := context.Background()
-	conn, err := systemd.NewSystemConnectionContext(ctx)
+	if num > 0 || len(tags) > 0 {
+		return s.forEachUnit(ctx, s.expandUnits(num, tags...), s.Parallel, "Started", startUnitOp)
+	}
+	conn, err := s.connect(ctx)
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
 	recv := make(chan string, 1)
-	if num > 0 {
-		for i := 1; i <= num; i++ {
-			name := s.Name + "@" + strconv.Itoa(i) + ".service"
-			_, err = conn.StartUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				s.logger.Warn(err.Error())
-				continue
-			}
-			v := <-recv
-			if v == "failed" {
-				s.logger.Error("Started [ " + name + " ] " + v)
-			} else {
-				s.logger.Info("Started [ " + name + " ] " + v)
-			}
-		}
-	} else if len(tags) > 0 {
-		for _, tag := range tags {
-			name := s.Name + "@" + tag + ".service"
-			_, err = conn.StartUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				s.logger.Warn(err.Error())
-				continue
-			}
-			v := <-recv
-			if v == "failed" {
-				s.logger.Error("Started [ " + name + " ] " + v)
-			} else {
-				s.logger.Info("Started [ " + name + " ] " + v)
-			}
-		}
-	} else {
-		name := s.Name + ".service"
+	name := s.Name + ".service"
+	_, err = conn.StartUnitContext(ctx, name, "fail", recv)
+	if err != nil {
+		name = s.Name + "@default.service"
 		_, err = conn.StartUnitContext(ctx, name, "fail", recv)
 		if err != nil {
-			name = s.Name + "@default.service"
-			_, err = conn.StartUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				return err
-			}
-		}
-		v := <-recv
-		if v == "failed" {
-			s.logger.Error("Started [ " + name + " ] " + v)
-		} else {
-			s.logger.Info("Started [ " + name + " ] " + v)
+			return err
 		}
 	}
+	v := <-recv
+	if v == "failed" {
+		s.logger.Error("Started [ " + name + " ] " + v)
+	} else {
+		s.logger.Info("Started [ " + name + " ] " + v)
+	}
 	return nil
 }
 
-// Stop the service
+// Stop the service. See Start for the single-unit-vs-bulk split.
 func (s *Systemd) Stop(all bool, tags ...string) error {
 	ctx := context.Background()
-	conn, err := systemd.NewSystemConnectionContext(ctx)
-	if err != nil {
-		return err
-	}
 	if all {
 		items, err := s.Status(false)
 		if err != nil {
 			return err
 		}
-		recv := make(chan string, 1)
-		for _, item := range items {
-			_, err = conn.StopUnitContext(ctx, item.Name, "fail", recv)
-			if err != nil {
-				s.logger.Warn(err.Error())
-				continue
-			}
-			v := <-recv
-			if v == "failed" {
-				s.logger.Error("Stop [ " + item.Name + "] " + v)
-			} else {
-				s.logger.Info("Stop [ " + item.Name + " ] " + v)
-			}
-		}
-	} else if len(tags) > 0 {
-		recv := make(chan string, 1)
-		for _, tag := range tags {
-			name := s.Name + "@" + tag + ".service"
-			_, err = conn.StopUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				s.logger.Warn(err.Error())
-				continue
-			}
-			v := <-recv
-			if v == "failed" {
-				s.logger.Error("Stop [" + name + "] " + v)
-			} else {
-				s.logger.Info("Stop [ " + name + " ] " + v)
-			}
+		units := make([]string, len(items))
+		for i, item := range items {
+			units[i] = item.Name
 		}
-	} else {
-		recv := make(chan string, 1)
-		name := s.Name + ".service"
+		return s.forEachUnit(ctx, units, s.Parallel, "Stop", stopUnitOp)
+	}
+	if len(tags) > 0 {
+		return s.forEachUnit(ctx, s.expandUnits(0, tags...), s.Parallel, "Stop", stopUnitOp)
+	}
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	recv := make(chan string, 1)
+	name := s.Name + ".service"
+	_, err = conn.StopUnitContext(ctx, name, "fail", recv)
+	if err != nil {
+		name = s.Name + "@default.service"
 		_, err = conn.StopUnitContext(ctx, name, "fail", recv)
 		if err != nil {
-			name = s.Name + "@default.service"
-			_, err = conn.StopUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				return err
-			}
-		}
-		v := <-recv
-		if v == "failed" {
-			s.logger.Error("Stop [" + name + "] " + v)
-		} else {
-			s.logger.Info("Stop [ " + name + " ] " + v)
+			return err
 		}
 	}
+	v := <-recv
+	if v == "failed" {
+		s.logger.Error("Stop [" + name + "] " + v)
+	} else {
+		s.logger.Info("Stop [ " + name + " ] " + v)
+	}
 	return nil
 }
 
@@ -212,17 +235,19 @@ func fileExists(name string) bool {
 	return err == nil && !fi.IsDir()
 }
 
-// Enable the service
+// Enable the service, plus its companion .timer/.socket units if installed.
 func (s *Systemd) Enable(tags ...string) (err error) {
-	var target string
-	origin := "/etc/systemd/system/" + s.Name + "@.service"
+	enabled := false
+	origin := s.UnitFile(true)
 	if fileExists(origin) {
-		target = "/etc/systemd/system/multi-user.target.wants/%s@%s.service"
+		if err = os.MkdirAll(s.wantsDir(), 0755); err != nil {
+			return err
+		}
 		if len(tags) == 0 {
 			tags = []string{"default"}
 		}
 		for _, tag := range tags {
-			_target := fmt.Sprintf(target, s.Name, tag)
+			_target := filepath.Join(s.wantsDir(), s.Name+"@"+tag+".service")
 			err = os.Symlink(origin, _target)
 			if err != nil {
 				s.logger.Error("Failed to create symlink", zap.String("origin", origin), zap.String("target", _target), zap.Error(err))
@@ -230,25 +255,55 @@ func (s *Systemd) Enable(tags ...string) (err error) {
 				s.logger.Info("Created symlink", zap.String("target", _target), zap.String("origin", origin))
 			}
 		}
-		return nil
+		enabled = true
+	} else {
+		origin = s.UnitFile(false)
+		if fileExists(origin) {
+			if err = os.MkdirAll(s.wantsDir(), 0755); err != nil {
+				return err
+			}
+			target := filepath.Join(s.wantsDir(), s.Name+".service")
+			err = os.Symlink(origin, target)
+			if err != nil {
+				s.logger.Error("Failed to create symlink", zap.String("origin", origin), zap.String("target", target), zap.Error(err))
+			} else {
+				s.logger.Info("Created symlink", zap.String("target", target), zap.String("origin", origin))
+			}
+			enabled = true
+		}
 	}
-	origin = "/etc/systemd/system/" + s.Name + ".service"
-	if fileExists(origin) {
-		target := "/etc/systemd/system/multi-user.target.wants/" + s.Name + ".service"
-		err = os.Symlink(origin, target)
-		if err != nil {
-			s.logger.Error("Failed to create symlink", zap.String("origin", origin), zap.String("target", target), zap.Error(err))
+	if !enabled {
+		return errors.New("service is not installed")
+	}
+
+	if fileExists(s.TimerFile()) {
+		if err = os.MkdirAll(s.timerWantsDir(), 0755); err != nil {
+			return err
+		}
+		target := filepath.Join(s.timerWantsDir(), s.Name+".timer")
+		if err = os.Symlink(s.TimerFile(), target); err != nil {
+			s.logger.Error("Failed to create symlink", zap.String("origin", s.TimerFile()), zap.String("target", target), zap.Error(err))
 		} else {
-			s.logger.Info("Created symlink", zap.String("target", target), zap.String("origin", origin))
+			s.logger.Info("Created symlink", zap.String("target", target), zap.String("origin", s.TimerFile()))
 		}
-		return nil
 	}
-	return errors.New("service is not installed")
+	if fileExists(s.SocketFile()) {
+		if err = os.MkdirAll(s.socketWantsDir(), 0755); err != nil {
+			return err
+		}
+		target := filepath.Join(s.socketWantsDir(), s.Name+".socket")
+		if err = os.Symlink(s.SocketFile(), target); err != nil {
+			s.logger.Error("Failed to create symlink", zap.String("origin", s.SocketFile()), zap.String("target", target), zap.Error(err))
+		} else {
+			s.logger.Info("Created symlink", zap.String("target", target), zap.String("origin", s.SocketFile()))
+		}
+	}
+	return nil
 }
 
-// Disable the service
+// Disable the service, plus its companion .timer/.socket units if installed.
 func (s *Systemd) Disable(tags ...string) (err error) {
-	target := "/etc/systemd/system/multi-user.target.wants/%s@%s.service"
+	target := filepath.Join(s.wantsDir(), "%s@%s.service")
 	if len(tags) > 0 {
 		for _, tag := range tags {
 			err = os.Remove(fmt.Sprintf(target, s.Name, tag))
@@ -256,174 +311,133 @@ func (s *Systemd) Disable(tags ...string) (err error) {
 				s.logger.Error("Failed to remove symlink", zap.String("target", target), zap.Error(err))
 			}
 		}
-		return nil
+		return s.disableTimerSocket(target)
 	}
-	err = os.Remove("/etc/systemd/system/multi-user.target.wants/" + s.Name + "@default.service")
+	err = os.Remove(filepath.Join(s.wantsDir(), s.Name+"@default.service"))
 	if err != nil {
 		s.logger.Warn("Failed to remove symlink", zap.String("target", target), zap.Error(err))
 	}
-	err = os.Remove("/etc/systemd/system/multi-user.target.wants/" + s.Name + ".service")
+	err = os.Remove(filepath.Join(s.wantsDir(), s.Name+".service"))
 	if err != nil {
 		s.logger.Warn("Failed to remove symlink", zap.String("target", target), zap.Error(err))
 	}
-	return nil
+	return s.disableTimerSocket(target)
 }
 
 // Kill the service
 func (s *Systemd) Kill(all bool, tags ...string) error {
 	ctx := context.Background()
-	conn, err := systemd.NewSystemConnectionContext(ctx)
-	if err != nil {
-		return err
-	}
 	if all {
 		items, err := s.Status(false)
 		if err != nil {
 			return err
 		}
-		for _, item := range items {
-			conn.KillUnitContext(ctx, item.Name, 9)
+		units := make([]string, len(items))
+		for i, item := range items {
+			units[i] = item.Name
 		}
-	} else if len(tags) > 0 {
-		for _, tag := range tags {
-			conn.KillUnitContext(ctx, s.Name+"@"+tag+".service", 9)
-		}
-	} else {
-		conn.KillUnitContext(ctx, s.Name+"default.service", 9)
-		conn.KillUnitContext(ctx, s.Name+"@default.service", 9)
+		return s.forEachUnit(ctx, units, s.Parallel, "Killed", killUnitOp)
+	}
+	if len(tags) > 0 {
+		return s.forEachUnit(ctx, s.expandUnits(0, tags...), s.Parallel, "Killed", killUnitOp)
+	}
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
 	}
+	defer conn.Close()
+	conn.KillUnitContext(ctx, s.Name+".service", 9)
+	conn.KillUnitContext(ctx, s.Name+"@default.service", 9)
 	return nil
 }
 
-// Restart the service
+// Restart the service. See Start for the single-unit-vs-bulk split.
 func (s *Systemd) Restart(all bool, tags ...string) error {
 	ctx := context.Background()
-	conn, err := systemd.NewSystemConnectionContext(ctx)
-	if err != nil {
-		return err
-	}
 	if all {
 		items, err := s.Status(false)
 		if err != nil {
 			return err
 		}
-		recv := make(chan string, 1)
-		for _, item := range items {
-			_, err = conn.RestartUnitContext(ctx, item.Name, "fail", recv)
-			if err != nil {
-				s.logger.Warn(err.Error())
-				continue
-			}
-			v := <-recv
-			if v == "failed" {
-				s.logger.Error("Restarted [ " + item.Name + "] " + v)
-			} else {
-				s.logger.Info("Restarted [ " + item.Name + " ] " + v)
-			}
-		}
-	} else if len(tags) > 0 {
-		recv := make(chan string, 1)
-		for _, tag := range tags {
-			name := s.Name + "@" + tag + ".service"
-			_, err = conn.RestartUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				s.logger.Warn(err.Error())
-				continue
-			}
-			v := <-recv
-			if v == "failed" {
-				s.logger.Error("Restarted [ " + name + " ] " + v)
-			} else {
-				s.logger.Info("Restarted [ " + name + " ] " + v)
-			}
+		units := make([]string, len(items))
+		for i, item := range items {
+			units[i] = item.Name
 		}
-	} else {
-		recv := make(chan string, 1)
-		name := s.Name + ".service"
+		return s.forEachUnit(ctx, units, s.Parallel, "Restarted", restartUnitOp)
+	}
+	if len(tags) > 0 {
+		return s.forEachUnit(ctx, s.expandUnits(0, tags...), s.Parallel, "Restarted", restartUnitOp)
+	}
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	recv := make(chan string, 1)
+	name := s.Name + ".service"
+	_, err = conn.RestartUnitContext(ctx, name, "fail", recv)
+	if err != nil {
+		name = s.Name + "@default.service"
 		_, err = conn.RestartUnitContext(ctx, name, "fail", recv)
 		if err != nil {
-			name = s.Name + "@default.service"
-			_, err = conn.RestartUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				return err
-			}
-		}
-		v := <-recv
-		if v == "failed" {
-			s.logger.Error("Restarted [ " + name + " ] " + v)
-		} else {
-			s.logger.Info("Restarted [ " + name + " ] " + v)
+			return err
 		}
 	}
+	v := <-recv
+	if v == "failed" {
+		s.logger.Error("Restarted [ " + name + " ] " + v)
+	} else {
+		s.logger.Info("Restarted [ " + name + " ] " + v)
+	}
 	return nil
 }
 
-// Reload the service
+// Reload the service. See Start for the single-unit-vs-bulk split.
 func (s *Systemd) Reload(all bool, tags ...string) error {
 	s.logger.Info("Reloading... " + s.Name)
 	ctx := context.Background()
-	conn, err := systemd.NewSystemConnectionContext(ctx)
-	if err != nil {
-		return err
-	}
 	if all {
 		items, err := s.Status(false)
 		if err != nil {
 			return err
 		}
-		recv := make(chan string, 1)
-		for _, item := range items {
-			_, err = conn.ReloadOrRestartUnitContext(ctx, item.Name, "fail", recv)
-			if err != nil {
-				return err
-			}
-			v := <-recv
-			if v == "failed" {
-				s.logger.Error("Reloaded [ " + item.Name + "] " + v)
-			} else {
-				s.logger.Info("Reloaded [ " + item.Name + " ] " + v)
-			}
+		units := make([]string, len(items))
+		for i, item := range items {
+			units[i] = item.Name
 		}
-	} else if len(tags) > 0 {
-		recv := make(chan string, 1)
-		for _, tag := range tags {
-			name := s.Name + "@" + tag + ".service"
-			_, err = conn.ReloadOrRestartUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				s.logger.Warn(err.Error())
-			}
-			v := <-recv
-			if v == "failed" {
-				s.logger.Error("Reloaded [ " + name + " ] " + v)
-			} else {
-				s.logger.Info("Reloaded [ " + name + " ] " + v)
-			}
-		}
-	} else {
-		recv := make(chan string, 1)
-		name := s.Name + ".service"
+		return s.forEachUnit(ctx, units, s.Parallel, "Reloaded", reloadUnitOp)
+	}
+	if len(tags) > 0 {
+		return s.forEachUnit(ctx, s.expandUnits(0, tags...), s.Parallel, "Reloaded", reloadUnitOp)
+	}
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	recv := make(chan string, 1)
+	name := s.Name + ".service"
+	_, err = conn.ReloadOrRestartUnitContext(ctx, name, "fail", recv)
+	if err != nil {
+		name = s.Name + "@default.service"
 		_, err = conn.ReloadOrRestartUnitContext(ctx, name, "fail", recv)
 		if err != nil {
-			name = s.Name + "@default.service"
-			_, err = conn.ReloadOrRestartUnitContext(ctx, name, "fail", recv)
-			if err != nil {
-				return err
-			}
-		}
-		v := <-recv
-		if v == "failed" {
-			s.logger.Error("Reloaded [ " + name + " ] " + v)
-		} else {
-			s.logger.Info("Reloaded [ " + name + " ] " + v)
+			return err
 		}
 	}
+	v := <-recv
+	if v == "failed" {
+		s.logger.Error("Reloaded [ " + name + " ] " + v)
+	} else {
+		s.logger.Info("Reloaded [ " + name + " ] " + v)
+	}
 	return nil
 }
 
 // Status - Get service status
 func (s *Systemd) Status(show bool) ([]systemd.UnitStatus, error) {
 	ctx := context.Background()
-	conn, err := systemd.NewSystemConnectionContext(ctx)
+	conn, err := s.connect(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,168 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnitOptions configures the [Unit]/[Service] sections CreateUnit renders.
+// A nil *UnitOptions means "use the previous minimal defaults" (Restart=always,
+// After=network.target, and WantedBy chosen by scope as CreateUnit describes).
+type UnitOptions struct {
+	// Restart policy: "no", "on-success", "on-failure", "on-abnormal",
+	// "on-watchdog", "on-abort", or "always". Defaults to "always".
+	Restart    string
+	RestartSec int
+
+	TimeoutStartSec int
+	TimeoutStopSec  int
+
+	After    []string
+	Requires []string
+	Wants    []string
+	PartOf   []string
+
+	WorkingDirectory string
+	Environment      []string // "KEY=VALUE" pairs
+	EnvironmentFile  []string
+
+	User  string
+	Group string
+
+	LimitNOFILE int
+	LimitNPROC  int
+	MemoryMax   string
+	CPUQuota    string
+
+	KillMode   string
+	KillSignal string
+
+	// WatchdogSec enables sd_notify WATCHDOG=1 keep-alive pings; the managed
+	// process is expected to call (*Systemd).NotifyWatchdog at an interval
+	// shorter than this.
+	WatchdogSec int
+
+	ExecReload    string
+	ExecStartPre  []string
+	ExecStartPost []string
+
+	// Timer, when set, makes Install also write and manage a companion
+	// name.timer unit that triggers this service on a schedule.
+	Timer *TimerOptions
+	// Socket, when set, makes Install also write and manage a companion
+	// name.socket unit that triggers this service on socket activation.
+	Socket *SocketOptions
+}
+
+func (o *UnitOptions) restart() string {
+	if o == nil || o.Restart == "" {
+		return "always"
+	}
+	return o.Restart
+}
+
+// CreateUnit renders a systemd unit file for name, running execPath with the
+// given arguments. When multi is true a template unit (name@.service) is
+// produced and %i is appended so the instance tag reaches the process. A nil
+// opts produces the same minimal unit CreateUnit has always emitted. scope
+// determines the [Install] WantedBy target: multi-user.target for
+// ScopeSystem, or default.target for ScopeUser, matching wantsDir so a plain
+// `systemctl [--user] enable` on the generated file agrees with this
+// package's own Enable.
+func CreateUnit(multi bool, name, desc, execPath string, scope Scope, opts *UnitOptions, args ...string) ([]byte, error) {
+	execStart := execPath
+	if len(args) > 0 {
+		execStart += " " + strings.Join(args, " ")
+	}
+	if multi {
+		execStart += " %i"
+	}
+
+	var b strings.Builder
+
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", desc)
+	after := []string{"network.target"}
+	if opts != nil && len(opts.After) > 0 {
+		after = opts.After
+	}
+	fmt.Fprintf(&b, "After=%s\n", strings.Join(after, " "))
+	if opts != nil {
+		if len(opts.Requires) > 0 {
+			fmt.Fprintf(&b, "Requires=%s\n", strings.Join(opts.Requires, " "))
+		}
+		if len(opts.Wants) > 0 {
+			fmt.Fprintf(&b, "Wants=%s\n", strings.Join(opts.Wants, " "))
+		}
+		if len(opts.PartOf) > 0 {
+			fmt.Fprintf(&b, "PartOf=%s\n", strings.Join(opts.PartOf, " "))
+		}
+	}
+
+	b.WriteString("\n[Service]\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", execStart)
+	fmt.Fprintf(&b, "Restart=%s\n", opts.restart())
+	if opts != nil {
+		if opts.RestartSec > 0 {
+			fmt.Fprintf(&b, "RestartSec=%d\n", opts.RestartSec)
+		}
+		if opts.TimeoutStartSec > 0 {
+			fmt.Fprintf(&b, "TimeoutStartSec=%d\n", opts.TimeoutStartSec)
+		}
+		if opts.TimeoutStopSec > 0 {
+			fmt.Fprintf(&b, "TimeoutStopSec=%d\n", opts.TimeoutStopSec)
+		}
+		if opts.WorkingDirectory != "" {
+			fmt.Fprintf(&b, "WorkingDirectory=%s\n", opts.WorkingDirectory)
+		}
+		for _, env := range opts.Environment {
+			fmt.Fprintf(&b, "Environment=%s\n", env)
+		}
+		for _, file := range opts.EnvironmentFile {
+			fmt.Fprintf(&b, "EnvironmentFile=%s\n", file)
+		}
+		if opts.User != "" {
+			fmt.Fprintf(&b, "User=%s\n", opts.User)
+		}
+		if opts.Group != "" {
+			fmt.Fprintf(&b, "Group=%s\n", opts.Group)
+		}
+		if opts.LimitNOFILE > 0 {
+			fmt.Fprintf(&b, "LimitNOFILE=%d\n", opts.LimitNOFILE)
+		}
+		if opts.LimitNPROC > 0 {
+			fmt.Fprintf(&b, "LimitNPROC=%d\n", opts.LimitNPROC)
+		}
+		if opts.MemoryMax != "" {
+			fmt.Fprintf(&b, "MemoryMax=%s\n", opts.MemoryMax)
+		}
+		if opts.CPUQuota != "" {
+			fmt.Fprintf(&b, "CPUQuota=%s\n", opts.CPUQuota)
+		}
+		if opts.KillMode != "" {
+			fmt.Fprintf(&b, "KillMode=%s\n", opts.KillMode)
+		}
+		if opts.KillSignal != "" {
+			fmt.Fprintf(&b, "KillSignal=%s\n", opts.KillSignal)
+		}
+		if opts.WatchdogSec > 0 {
+			fmt.Fprintf(&b, "WatchdogSec=%d\n", opts.WatchdogSec)
+		}
+		if opts.ExecReload != "" {
+			fmt.Fprintf(&b, "ExecReload=%s\n", opts.ExecReload)
+		}
+		for _, pre := range opts.ExecStartPre {
+			fmt.Fprintf(&b, "ExecStartPre=%s\n", pre)
+		}
+		for _, post := range opts.ExecStartPost {
+			fmt.Fprintf(&b, "ExecStartPost=%s\n", post)
+		}
+	}
+
+	wantedBy := "multi-user.target"
+	if scope == ScopeUser {
+		wantedBy = "default.target"
+	}
+	fmt.Fprintf(&b, "\n[Install]\nWantedBy=%s\n", wantedBy)
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,95 @@
+package systemd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	systemd "github.com/coreos/go-systemd/v22/dbus"
+)
+
+// UnitEvent is an ActiveState/SubState transition observed by Watch.
+type UnitEvent struct {
+	Name            string
+	PrevActiveState string
+	PrevSubState    string
+	ActiveState     string
+	SubState        string
+	Timestamp       time.Time
+}
+
+type watchState struct {
+	active string
+	sub    string
+}
+
+// Watch subscribes to dbus unit change signals and pushes a UnitEvent for
+// every ActiveState/SubState transition of units matching s.Name* (including
+// template instances such as foo@1.service) until ctx is done. This is a
+// genuine push feed: conn.Subscribe plus conn.SetSubStateSubscriber are fed
+// by dbus PropertiesChanged signals through the connection's own dispatch
+// loop, not a poll on a timer, so transitions are reported as soon as
+// systemd emits them rather than on the next tick of a ticker.
+func (s *Systemd) Watch(ctx context.Context) (<-chan UnitEvent, error) {
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = conn.Subscribe(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	prefix := s.Name
+	updates := make(chan *systemd.SubStateUpdate, 16)
+	errs := make(chan error, 16)
+	conn.SetSubStateSubscriber(updates, errs)
+
+	last := make(map[string]watchState)
+	out := make(chan UnitEvent)
+	go func() {
+		defer conn.Close()
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-errs:
+				if err != nil {
+					s.logger.Warn(err.Error())
+				}
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				if !strings.HasPrefix(update.UnitName, prefix) {
+					continue
+				}
+				prev := last[update.UnitName]
+
+				var active string
+				if prop, err := conn.GetUnitPropertyContext(ctx, update.UnitName, "ActiveState"); err == nil {
+					active, _ = prop.Value.Value().(string)
+				} else {
+					active = prev.active
+				}
+				last[update.UnitName] = watchState{active: active, sub: update.SubState}
+
+				event := UnitEvent{
+					Name:            update.UnitName,
+					PrevActiveState: prev.active,
+					PrevSubState:    prev.sub,
+					ActiveState:     active,
+					SubState:        update.SubState,
+					Timestamp:       time.Now(),
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}